@@ -0,0 +1,157 @@
+/*
+Copyright 2024 WJQserver Studio. WJQserver Studio 2.0 License.
+*/
+
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy 描述异步队列写满时的处理方式
+type OverflowPolicy int32
+
+const (
+	Block      OverflowPolicy = iota // 阻塞调用方直到队列有空位
+	DropNewest                       // 丢弃当前这条新日志
+	DropOldest                       // 丢弃队列中最旧的一条，为新日志腾出空间
+)
+
+// LoggerStats 是 Logger 异步管线的运行时统计信息
+type LoggerStats struct {
+	QueueDepth   int   // 当前队列中待写入的日志条数
+	Dropped      int64 // 因队列写满而被丢弃的日志总数
+	BytesWritten int64 // 已分发给各 Sink 的日志正文总字节数（近似值）
+}
+
+// SetAsync 开启异步写入：LogStruct/LogKVStruct 只将 Entry 放入一个容量为 bufferSize
+// 的有界队列，由一个专门的后台 goroutine 负责实际分发给各个 Sink，调用方不再被磁盘
+// IO 阻塞。policy 决定队列写满时的行为
+//
+// 重复调用会先停止并清空上一个队列，再按新参数重新启动
+func (l *Logger) SetAsync(bufferSize int, policy OverflowPolicy) {
+	core := l.core
+	core.asyncMu.Lock()
+	defer core.asyncMu.Unlock()
+
+	if core.asyncQueue != nil {
+		close(core.asyncStop)
+		<-core.asyncDone
+	}
+
+	core.asyncQueue = make(chan *Entry, bufferSize)
+	core.asyncPolicy.Store(policy)
+	core.asyncStop = make(chan struct{})
+	core.asyncDone = make(chan struct{})
+	atomic.StoreInt32(&core.asyncEnabled, 1)
+
+	go core.runAsyncWriter()
+}
+
+// Stats 返回异步管线的队列深度、丢弃计数和已写出字节数；未开启异步时队列深度恒为 0
+func (l *Logger) Stats() LoggerStats {
+	core := l.core
+	depth := 0
+	if core.asyncQueue != nil {
+		depth = len(core.asyncQueue)
+	}
+	return LoggerStats{
+		QueueDepth:   depth,
+		Dropped:      atomic.LoadInt64(&core.droppedLogs),
+		BytesWritten: atomic.LoadInt64(&core.bytesWritten),
+	}
+}
+
+// Flush 阻塞直到异步队列被完全消费、且所有已取出的 Entry 都分发完毕，或 ctx 被取消/超时
+//
+// 未开启异步时立即返回 nil
+func (l *Logger) Flush(ctx context.Context) error {
+	core := l.core
+	if atomic.LoadInt32(&core.asyncEnabled) == 0 || core.asyncQueue == nil {
+		return nil
+	}
+	for len(core.asyncQueue) > 0 || atomic.LoadInt64(&core.pendingDispatch) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	return nil
+}
+
+// dispatchTracked 在 pendingDispatch 计数下调用 core.dispatch：计数从 runAsyncWriter/
+// drainQueue 把 Entry 从队列取出那一刻起，到 dispatch（也就是各 Sink.Write）真正返回
+// 为止都保持非零，这样 Flush 即使看到队列已空，也知道还有一条日志没写完
+func (core *loggerCore) dispatchTracked(entry *Entry) {
+	atomic.AddInt64(&core.pendingDispatch, 1)
+	defer atomic.AddInt64(&core.pendingDispatch, -1)
+	core.dispatch(entry)
+}
+
+// enqueue 按 OverflowPolicy 把 entry 放入异步队列
+func (core *loggerCore) enqueue(entry *Entry) {
+	policy, _ := core.asyncPolicy.Load().(OverflowPolicy)
+
+	switch policy {
+	case DropNewest:
+		select {
+		case core.asyncQueue <- entry:
+		default:
+			atomic.AddInt64(&core.droppedLogs, 1)
+		}
+	case DropOldest:
+		select {
+		case core.asyncQueue <- entry:
+		default:
+			select {
+			case <-core.asyncQueue:
+				atomic.AddInt64(&core.droppedLogs, 1)
+			default:
+			}
+			select {
+			case core.asyncQueue <- entry:
+			default:
+				atomic.AddInt64(&core.droppedLogs, 1)
+			}
+		}
+	default: // Block
+		core.asyncQueue <- entry
+	}
+}
+
+// runAsyncWriter 是异步写入的后台 goroutine：不断从队列取出 Entry 分发给各 Sink，
+// 收到停止信号后会先排空队列剩余的日志，再退出
+func (core *loggerCore) runAsyncWriter() {
+	defer close(core.asyncDone)
+
+	for {
+		select {
+		case entry, ok := <-core.asyncQueue:
+			if !ok {
+				return
+			}
+			core.dispatchTracked(entry)
+		case <-core.asyncStop:
+			core.drainQueue()
+			return
+		}
+	}
+}
+
+// drainQueue 在停止异步写入前，同步分发队列里剩余的全部日志
+func (core *loggerCore) drainQueue() {
+	for {
+		select {
+		case entry, ok := <-core.asyncQueue:
+			if !ok {
+				return
+			}
+			core.dispatchTracked(entry)
+		default:
+			return
+		}
+	}
+}
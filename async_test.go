@@ -0,0 +1,174 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingSink 在每次 Write 开始时通过 entered 发出信号，然后阻塞在 release 上，
+// 用于在测试中精确控制异步写入 goroutine 的推进节奏
+type blockingSink struct {
+	entered chan struct{}
+	release chan struct{}
+
+	mu  sync.Mutex
+	got []*Entry
+}
+
+func (s *blockingSink) Write(entry *Entry) error {
+	s.entered <- struct{}{}
+	<-s.release
+	s.mu.Lock()
+	s.got = append(s.got, entry)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *blockingSink) Close() error { return nil }
+
+// drainEntered 持续消费 entered 信号，避免 blockingSink 在 release 关闭后
+// 继续处理排队中的日志时卡在向 entered 发送信号上
+func drainEntered(s *blockingSink) {
+	for range s.entered {
+	}
+}
+
+func (s *blockingSink) messages() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, len(s.got))
+	for i, e := range s.got {
+		out[i] = e.Message
+	}
+	return out
+}
+
+// TestSetAsyncDropNewest 验证队列写满后 DropNewest 会丢弃新来的日志并计入 Stats().Dropped，
+// 已在队列中的日志保持不变
+func TestSetAsyncDropNewest(t *testing.T) {
+	l := NewLogger()
+	sink := &blockingSink{entered: make(chan struct{}), release: make(chan struct{})}
+	l.AddSink(sink, LevelDump)
+	l.SetAsync(2, DropNewest)
+
+	l.LogInfoStruct("e1") // 被后台 goroutine 立即取走，阻塞在 Write 里
+	<-sink.entered
+	go drainEntered(sink) // 之后的 Write 调用不再需要被测试逐一同步
+
+	l.LogInfoStruct("e2") // 进入队列，深度 1
+	l.LogInfoStruct("e3") // 进入队列，深度 2（已满）
+	l.LogInfoStruct("e4") // 队列已满，被丢弃
+
+	stats := l.Stats()
+	if stats.Dropped != 1 {
+		t.Errorf("Dropped = %d, want 1", stats.Dropped)
+	}
+	if stats.QueueDepth != 2 {
+		t.Errorf("QueueDepth = %d, want 2", stats.QueueDepth)
+	}
+
+	close(sink.release)
+	if err := l.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	got := sink.messages()
+	want := []string{"e1", "e2", "e3"}
+	if len(got) != len(want) {
+		t.Fatalf("messages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("messages[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+// delaySink 的 Write 在返回前睡眠固定时长，用于制造一个"已从队列取出但
+// dispatch 尚未返回"的窗口
+type delaySink struct {
+	delay time.Duration
+
+	mu      sync.Mutex
+	written bool
+}
+
+func (s *delaySink) Write(entry *Entry) error {
+	time.Sleep(s.delay)
+	s.mu.Lock()
+	s.written = true
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *delaySink) Close() error { return nil }
+
+func (s *delaySink) wasWritten() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.written
+}
+
+// TestFlushWaitsForInFlightDispatch 验证 Flush 不会在后台写入 goroutine 刚把 Entry
+// 从队列取出、dispatch（即 Sink.Write）仍在执行时就提前返回——只看队列长度是否为 0
+// 不够，因为取出和分发完成之间存在窗口期
+func TestFlushWaitsForInFlightDispatch(t *testing.T) {
+	l := NewLogger()
+	sink := &delaySink{delay: 50 * time.Millisecond}
+	l.AddSink(sink, LevelDump)
+	l.SetAsync(4, Block)
+
+	l.LogInfoStruct("e1")
+	// 给后台写入 goroutine 一点时间把 e1 取出并进入 Write（此时队列已空，但 dispatch 未返回）
+	time.Sleep(10 * time.Millisecond)
+
+	if err := l.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if !sink.wasWritten() {
+		t.Fatal("Flush returned before the in-flight dispatch finished writing to the sink")
+	}
+}
+
+// TestSetAsyncDropOldest 验证队列写满后 DropOldest 会丢弃队列中最旧的一条，
+// 为新日志腾出空间，同样计入 Stats().Dropped
+func TestSetAsyncDropOldest(t *testing.T) {
+	l := NewLogger()
+	sink := &blockingSink{entered: make(chan struct{}), release: make(chan struct{})}
+	l.AddSink(sink, LevelDump)
+	l.SetAsync(2, DropOldest)
+
+	l.LogInfoStruct("e1") // 被后台 goroutine 立即取走，阻塞在 Write 里
+	<-sink.entered
+	go drainEntered(sink) // 之后的 Write 调用不再需要被测试逐一同步
+
+	l.LogInfoStruct("e2") // 进入队列，深度 1
+	l.LogInfoStruct("e3") // 进入队列，深度 2（已满）
+	l.LogInfoStruct("e4") // 队列已满，丢弃最旧的 e2，e4 入队
+
+	stats := l.Stats()
+	if stats.Dropped != 1 {
+		t.Errorf("Dropped = %d, want 1", stats.Dropped)
+	}
+	if stats.QueueDepth != 2 {
+		t.Errorf("QueueDepth = %d, want 2", stats.QueueDepth)
+	}
+
+	close(sink.release)
+	if err := l.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	got := sink.messages()
+	want := []string{"e1", "e3", "e4"}
+	if len(got) != len(want) {
+		t.Fatalf("messages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("messages[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
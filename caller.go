@@ -0,0 +1,82 @@
+/*
+Copyright 2024 WJQserver Studio. WJQserver Studio 2.0 License.
+*/
+
+package logger
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// funcCache 按 pc 缓存函数名，避免每条日志都重新调用 runtime.FuncForPC
+var funcCache sync.Map // map[uintptr]string
+
+// maxCallerFrames 是 resolveCaller 向外遍历栈帧时检查的最大帧数上限，
+// 避免栈帧信息异常时陷入死循环
+const maxCallerFrames = 32
+
+// packagePrefix 是本包的导入路径，由 resolveCaller 在运行时用来识别并跳过
+// 本包自身的包装函数帧
+//
+// 不管调用方是经由全局函数（LogInfo）还是直接调用 Logger 实例的方法
+// （l.LogInfoStruct），也不管中间包了几层（LogXxxStruct/LogfStruct/
+// LogKVStruct/LogXxxCtxStruct/logWithFields），它们的函数名都以
+// packagePrefix 为前缀，resolveCaller 据此一路跳过，直到第一个包外部的帧——
+// 也就是真正的业务调用处，因此不需要像过去那样为每条调用链手动维护固定的跳帧数
+var packagePrefix = func() string {
+	pc, _, _, ok := runtime.Caller(0)
+	if !ok {
+		return ""
+	}
+	name := lookupFuncName(pc)
+	if idx := strings.LastIndex(name, "/"); idx >= 0 {
+		if dot := strings.Index(name[idx:], "."); dot >= 0 {
+			return name[:idx+dot]
+		}
+	}
+	if dot := strings.Index(name, "."); dot >= 0 {
+		return name[:dot]
+	}
+	return name
+}()
+
+// lookupFuncName 返回 pc 对应的函数名，结果按 pc 缓存
+func lookupFuncName(pc uintptr) string {
+	if name, cached := funcCache.Load(pc); cached {
+		return name.(string)
+	}
+	name := ""
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		name = fn.Name()
+	}
+	funcCache.Store(pc, name)
+	return name
+}
+
+// resolveCaller 从 logWithFields 调用处开始向外遍历调用栈，自动跳过所有属于
+// 本包自身的包装函数帧，定位到第一个包外部的帧；extraSkip 在此基础上再额外
+// 跳过指定层数，供业务代码通过 SetCallerSkip 为自己的日志封装函数去除干扰
+func resolveCaller(extraSkip int) (file string, line int, funcName string, ok bool) {
+	// skip=1 是 resolveCaller 自身调用处（logWithFields 里调用 resolveCaller 那一行），
+	// 以此为起点向外遍历
+	for i := 1; i < maxCallerFrames; i++ {
+		pc, fullFile, l, callerOK := runtime.Caller(i)
+		if !callerOK {
+			return "", 0, "", false
+		}
+
+		name := lookupFuncName(pc)
+		if packagePrefix != "" && strings.HasPrefix(name, packagePrefix+".") {
+			continue
+		}
+		if extraSkip > 0 {
+			extraSkip--
+			continue
+		}
+		return filepath.Base(fullFile), l, name, true
+	}
+	return "", 0, "", false
+}
@@ -0,0 +1,119 @@
+package logger_test
+
+// 本文件特意放在 logger_test 外部测试包（而不是 logger_test.go 所在的 logger 包）里：
+// resolveCaller 通过包路径前缀识别并跳过本包自身的包装函数帧，只有从一个不同的包路径
+// 调用，才能像真正的外部使用者一样验证它到底跳到了哪一帧
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	logger "github.com/WJQSERVER-STUDIO/logger"
+)
+
+// captureSink 记录收到的全部 Entry，供断言调用处信息使用
+type captureSink struct {
+	mu      sync.Mutex
+	entries []*logger.Entry
+}
+
+func (s *captureSink) Write(entry *logger.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *captureSink) Close() error { return nil }
+
+func (s *captureSink) last() *logger.Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) == 0 {
+		return nil
+	}
+	return s.entries[len(s.entries)-1]
+}
+
+// TestCallerLineGlobalFunctions 验证格式化路径（LogInfo）和结构化字段路径（LogInfoKV）
+// 都能解析出真实调用处的文件名和行号，而不是包内部包装函数自身的位置
+func TestCallerLineGlobalFunctions(t *testing.T) {
+	sink := &captureSink{}
+	logger.AddSink(sink, logger.LevelDump)
+	logger.SetCallerEnabled(true)
+	defer func() {
+		logger.RemoveSink(sink)
+		logger.SetCallerEnabled(false)
+	}()
+
+	_, _, callSite, _ := runtime.Caller(0)
+	logger.LogInfo("hello %s", "world")
+	wantLine := callSite + 1
+
+	entry := sink.last()
+	if entry == nil {
+		t.Fatal("LogInfo: no entry captured")
+	}
+	if entry.CallerFile != "caller_external_test.go" {
+		t.Errorf("LogInfo: CallerFile = %q, want caller_external_test.go", entry.CallerFile)
+	}
+	if entry.CallerLine != wantLine {
+		t.Errorf("LogInfo: CallerLine = %d, want %d", entry.CallerLine, wantLine)
+	}
+
+	_, _, callSite, _ = runtime.Caller(0)
+	logger.LogInfoKV("hello", logger.String("who", "world"))
+	wantLine = callSite + 1
+
+	entry = sink.last()
+	if entry == nil {
+		t.Fatal("LogInfoKV: no entry captured")
+	}
+	if entry.CallerFile != "caller_external_test.go" {
+		t.Errorf("LogInfoKV: CallerFile = %q, want caller_external_test.go", entry.CallerFile)
+	}
+	if entry.CallerLine != wantLine {
+		t.Errorf("LogInfoKV: CallerLine = %d, want %d", entry.CallerLine, wantLine)
+	}
+}
+
+// TestCallerLineDirectInstanceMethods 验证直接调用 NewLogger() 实例的 *Struct 方法
+// （不经过任何全局包装函数）同样能解析出真实调用处，而不是多跳过一帧之后的垃圾位置——
+// 这是此前 defaultCallerSkip 固定跳帧数方案的已知缺陷
+func TestCallerLineDirectInstanceMethods(t *testing.T) {
+	l := logger.NewLogger()
+	sink := &captureSink{}
+	l.AddSink(sink, logger.LevelDump)
+	l.SetCallerEnabled(true)
+
+	_, _, callSite, _ := runtime.Caller(0)
+	l.LogInfoStruct("hello %s", "world")
+	wantLine := callSite + 1
+
+	entry := sink.last()
+	if entry == nil {
+		t.Fatal("LogInfoStruct: no entry captured")
+	}
+	if entry.CallerFile != "caller_external_test.go" {
+		t.Errorf("LogInfoStruct: CallerFile = %q, want caller_external_test.go", entry.CallerFile)
+	}
+	if entry.CallerLine != wantLine {
+		t.Errorf("LogInfoStruct: CallerLine = %d, want %d", entry.CallerLine, wantLine)
+	}
+
+	_, _, callSite, _ = runtime.Caller(0)
+	l.LogInfoKVStruct("hello", logger.String("who", "world"))
+	wantLine = callSite + 1
+
+	entry = sink.last()
+	if entry == nil {
+		t.Fatal("LogInfoKVStruct: no entry captured")
+	}
+	if entry.CallerFile != "caller_external_test.go" {
+		t.Errorf("LogInfoKVStruct: CallerFile = %q, want caller_external_test.go", entry.CallerFile)
+	}
+	if entry.CallerLine != wantLine {
+		t.Errorf("LogInfoKVStruct: CallerLine = %d, want %d", entry.CallerLine, wantLine)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2024 WJQserver Studio. WJQserver Studio 2.0 License.
+*/
+
+package logger
+
+import (
+	"context"
+	"sync"
+)
+
+// contextExtractors 是全局注册的 context 字段提取器，RegisterContextExtractor 负责追加，
+// WithContext 在记录日志时依次调用它们收集 trace_id/span_id/tenant_id 等字段
+var (
+	contextExtractorsMu sync.RWMutex
+	contextExtractors   []func(ctx context.Context) []Field
+)
+
+// RegisterContextExtractor 注册一个从 context.Context 提取日志字段的函数
+//
+// 典型用法是从 OpenTelemetry span 提取 trace_id/span_id，或从中间件注入的
+// context 取出 request-id、tenant-id，使相关日志无需在每个调用点手动传递这些字段
+func RegisterContextExtractor(extractor func(ctx context.Context) []Field) {
+	contextExtractorsMu.Lock()
+	defer contextExtractorsMu.Unlock()
+	contextExtractors = append(contextExtractors, extractor)
+}
+
+// extractContextFields 依次调用所有已注册的提取器，合并它们返回的字段
+func extractContextFields(ctx context.Context) []Field {
+	contextExtractorsMu.RLock()
+	defer contextExtractorsMu.RUnlock()
+
+	if len(contextExtractors) == 0 {
+		return nil
+	}
+
+	var fields []Field
+	for _, extractor := range contextExtractors {
+		fields = append(fields, extractor(ctx)...)
+	}
+	return fields
+}
+
+// WithContext 返回一个绑定了 ctx 提取字段的子 Logger，等价于 l.With(extractContextFields(ctx)...)
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	fields := extractContextFields(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	return l.With(fields...)
+}
+
+// LogDumpCtx 在记录前附加 ctx 提取的字段
+func (l *Logger) LogDumpCtxStruct(ctx context.Context, format string, args ...interface{}) {
+	l.WithContext(ctx).LogfStruct(LevelDump, format, args...)
+}
+
+// LogDebugCtx 在记录前附加 ctx 提取的字段
+func (l *Logger) LogDebugCtxStruct(ctx context.Context, format string, args ...interface{}) {
+	l.WithContext(ctx).LogfStruct(LevelDebug, format, args...)
+}
+
+// LogInfoCtx 在记录前附加 ctx 提取的字段
+func (l *Logger) LogInfoCtxStruct(ctx context.Context, format string, args ...interface{}) {
+	l.WithContext(ctx).LogfStruct(LevelInfo, format, args...)
+}
+
+// LogWarningCtx 在记录前附加 ctx 提取的字段
+func (l *Logger) LogWarningCtxStruct(ctx context.Context, format string, args ...interface{}) {
+	l.WithContext(ctx).LogfStruct(LevelWarn, format, args...)
+}
+
+// LogErrorCtx 在记录前附加 ctx 提取的字段
+func (l *Logger) LogErrorCtxStruct(ctx context.Context, format string, args ...interface{}) {
+	l.WithContext(ctx).LogfStruct(LevelError, format, args...)
+}
+
+// WithContext 基于默认 Logger 返回一个绑定了 ctx 提取字段的子 Logger
+func WithContext(ctx context.Context) *Logger {
+	return defaultLogger.WithContext(ctx)
+}
+
+// 带 context 的日志记录函数，使用原有的函数命名风格
+func LogDumpCtx(ctx context.Context, format string, args ...interface{}) {
+	defaultLogger.LogDumpCtxStruct(ctx, format, args...)
+}
+
+func LogDebugCtx(ctx context.Context, format string, args ...interface{}) {
+	defaultLogger.LogDebugCtxStruct(ctx, format, args...)
+}
+
+func LogInfoCtx(ctx context.Context, format string, args ...interface{}) {
+	defaultLogger.LogInfoCtxStruct(ctx, format, args...)
+}
+
+func LogWarningCtx(ctx context.Context, format string, args ...interface{}) {
+	defaultLogger.LogWarningCtxStruct(ctx, format, args...)
+}
+
+func LogErrorCtx(ctx context.Context, format string, args ...interface{}) {
+	defaultLogger.LogErrorCtxStruct(ctx, format, args...)
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+// contextExtractors 是全局、只增不减的注册表（见 context.go），在同一测试二进制里
+// 会被其它测试共享，因此这里注册的提取器都必须只对自己专属的 ctx 键生效——对
+// context.Background() 这类"干净"的 ctx 一律返回 nil，这样测试之间才不会互相污染
+type ctxKeyTraceID struct{}
+type ctxKeyExtractorA struct{}
+type ctxKeyExtractorB struct{}
+
+// TestWithContextAppliesRegisteredExtractors 验证 RegisterContextExtractor 注册的提取器
+// 返回的字段会出现在经由 WithContext/LogInfoCtxStruct 记录的 Entry 上
+func TestWithContextAppliesRegisteredExtractors(t *testing.T) {
+	RegisterContextExtractor(func(ctx context.Context) []Field {
+		traceID, _ := ctx.Value(ctxKeyTraceID{}).(string)
+		if traceID == "" {
+			return nil
+		}
+		return []Field{String("trace_id", traceID)}
+	})
+
+	l := NewLogger()
+	sink := &captureSink{}
+	l.AddSink(sink, LevelDump)
+
+	ctx := context.WithValue(context.Background(), ctxKeyTraceID{}, "abc123")
+	l.LogInfoCtxStruct(ctx, "hello %s", "world")
+
+	entry := sink.last()
+	if entry == nil {
+		t.Fatal("LogInfoCtxStruct: no entry captured")
+	}
+	if !hasField(entry.Fields, "trace_id", "abc123") {
+		t.Errorf("entry.Fields = %+v, want it to contain trace_id=abc123", entry.Fields)
+	}
+}
+
+// TestWithContextComposesMultipleExtractors 验证多个已注册的提取器会被依次调用并合并字段
+func TestWithContextComposesMultipleExtractors(t *testing.T) {
+	RegisterContextExtractor(func(ctx context.Context) []Field {
+		v, _ := ctx.Value(ctxKeyExtractorA{}).(string)
+		if v == "" {
+			return nil
+		}
+		return []Field{String("extractor_a", v)}
+	})
+	RegisterContextExtractor(func(ctx context.Context) []Field {
+		v, _ := ctx.Value(ctxKeyExtractorB{}).(string)
+		if v == "" {
+			return nil
+		}
+		return []Field{String("extractor_b", v)}
+	})
+
+	l := NewLogger()
+	sink := &captureSink{}
+	l.AddSink(sink, LevelDump)
+
+	ctx := context.WithValue(context.Background(), ctxKeyExtractorA{}, "a")
+	ctx = context.WithValue(ctx, ctxKeyExtractorB{}, "b")
+	l.LogInfoCtxStruct(ctx, "hello")
+
+	entry := sink.last()
+	if entry == nil {
+		t.Fatal("LogInfoCtxStruct: no entry captured")
+	}
+	if !hasField(entry.Fields, "extractor_a", "a") {
+		t.Errorf("entry.Fields = %+v, want it to contain extractor_a=a", entry.Fields)
+	}
+	if !hasField(entry.Fields, "extractor_b", "b") {
+		t.Errorf("entry.Fields = %+v, want it to contain extractor_b=b", entry.Fields)
+	}
+}
+
+// TestWithContextNoExtractorFieldsReturnsSameLogger 验证当已注册的提取器对给定 ctx
+// 都不产生字段时，WithContext 按文档所述直接返回同一个 Logger（短路），不分配新的子
+// Logger；用 context.Background() 即可，因为本文件里注册的提取器都只认自己专属的键
+func TestWithContextNoExtractorFieldsReturnsSameLogger(t *testing.T) {
+	l := NewLogger()
+	got := l.WithContext(context.Background())
+	if got != l {
+		t.Error("WithContext: expected the same *Logger when no extractor yields fields")
+	}
+}
+
+// hasField 判断 fields 中是否包含一个键为 key、值为 value 的字符串字段
+func hasField(fields []Field, key, value string) bool {
+	for _, f := range fields {
+		if f.Key == key && f.String == value {
+			return true
+		}
+	}
+	return false
+}
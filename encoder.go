@@ -0,0 +1,197 @@
+/*
+Copyright 2024 WJQserver Studio. WJQserver Studio 2.0 License.
+*/
+
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Encoder 负责把一条 Entry 渲染成最终写出的字节，由各个 Sink 按需选择
+type Encoder interface {
+	Encode(entry *Entry) []byte
+}
+
+// encoderBufferPool 复用编码过程中用到的 bytes.Buffer，避免每条日志都分配
+var encoderBufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func getEncoderBuffer() *bytes.Buffer {
+	return encoderBufferPool.Get().(*bytes.Buffer)
+}
+
+func putEncoderBuffer(buf *bytes.Buffer) {
+	buf.Reset()
+	encoderBufferPool.Put(buf)
+}
+
+// cloneBytes 从池化的 buffer 中拷贝出一份独立的切片再返回给调用方
+func cloneBytes(buf *bytes.Buffer) []byte {
+	out := make([]byte, buf.Len())
+	copy(out, buf.Bytes())
+	return out
+}
+
+// levelName 返回日志等级对应的小写名称，供结构化编码器使用
+func levelName(level int) string {
+	switch level {
+	case LevelDump:
+		return "dump"
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "none"
+	}
+}
+
+// TextEncoder 渲染现有的人类可读文本格式，并在末尾以 key=value 的形式追加字段
+type TextEncoder struct{}
+
+// Encode 实现 Encoder 接口
+func (TextEncoder) Encode(entry *Entry) []byte {
+	buf := getEncoderBuffer()
+	defer putEncoderBuffer(buf)
+
+	buf.WriteString(formatTextEntry(entry))
+	writeFieldsLogfmt(buf, entry.Fields)
+
+	return cloneBytes(buf)
+}
+
+// LogfmtEncoder 以 logfmt（key=value 以空格分隔）格式渲染 Entry
+type LogfmtEncoder struct{}
+
+// Encode 实现 Encoder 接口
+func (LogfmtEncoder) Encode(entry *Entry) []byte {
+	buf := getEncoderBuffer()
+	defer putEncoderBuffer(buf)
+
+	buf.WriteString("time=")
+	buf.WriteString(entry.Time.Format(timeFormat))
+	buf.WriteString(" level=")
+	buf.WriteString(levelName(entry.Level))
+	if entry.CallerFile != "" {
+		buf.WriteString(" caller=")
+		buf.WriteString(entry.CallerFile)
+		buf.WriteByte(':')
+		buf.WriteString(strconv.Itoa(entry.CallerLine))
+	}
+	buf.WriteString(" msg=")
+	writeLogfmtValue(buf, entry.Message)
+	writeFieldsLogfmt(buf, entry.Fields)
+
+	return cloneBytes(buf)
+}
+
+// JSONEncoder 以单行 JSON 渲染 Entry
+type JSONEncoder struct{}
+
+// Encode 实现 Encoder 接口
+func (JSONEncoder) Encode(entry *Entry) []byte {
+	buf := getEncoderBuffer()
+	defer putEncoderBuffer(buf)
+
+	buf.WriteByte('{')
+	buf.WriteString(`"time":`)
+	writeJSONString(buf, entry.Time.Format(timeFormat))
+	buf.WriteString(`,"level":`)
+	writeJSONString(buf, levelName(entry.Level))
+	if entry.CallerFile != "" {
+		buf.WriteString(`,"caller":`)
+		writeJSONString(buf, fmt.Sprintf("%s:%d", entry.CallerFile, entry.CallerLine))
+		buf.WriteString(`,"func":`)
+		writeJSONString(buf, entry.CallerFunc)
+	}
+	buf.WriteString(`,"msg":`)
+	writeJSONString(buf, entry.Message)
+	for _, f := range entry.Fields {
+		buf.WriteByte(',')
+		writeJSONString(buf, f.Key)
+		buf.WriteByte(':')
+		writeJSONValue(buf, f.Value())
+	}
+	buf.WriteByte('}')
+
+	return cloneBytes(buf)
+}
+
+// writeFieldsLogfmt 以 " key=value" 的形式把 fields 依次写入 buf
+func writeFieldsLogfmt(buf *bytes.Buffer, fields []Field) {
+	for _, f := range fields {
+		buf.WriteByte(' ')
+		buf.WriteString(f.Key)
+		buf.WriteByte('=')
+		writeLogfmtValue(buf, f.Value())
+	}
+}
+
+// writeLogfmtValue 按 logfmt 习惯写入一个值：字符串在包含空白或引号时加双引号
+func writeLogfmtValue(buf *bytes.Buffer, v interface{}) {
+	switch val := v.(type) {
+	case string:
+		if needsLogfmtQuoting(val) {
+			buf.WriteString(strconv.Quote(val))
+		} else {
+			buf.WriteString(val)
+		}
+	case bool:
+		buf.WriteString(strconv.FormatBool(val))
+	case int64:
+		buf.WriteString(strconv.FormatInt(val, 10))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(val, 'f', -1, 64))
+	case time.Duration:
+		buf.WriteString(val.String())
+	case nil:
+		buf.WriteString("null")
+	default:
+		buf.WriteString(strconv.Quote(fmt.Sprintf("%v", val)))
+	}
+}
+
+// needsLogfmtQuoting 判断字符串是否需要加引号才能安全地写入 logfmt
+func needsLogfmtQuoting(s string) bool {
+	for _, r := range s {
+		if r == ' ' || r == '=' || r == '"' {
+			return true
+		}
+	}
+	return len(s) == 0
+}
+
+// writeJSONString 写入一个经过转义的 JSON 字符串
+func writeJSONString(buf *bytes.Buffer, s string) {
+	buf.WriteString(strconv.Quote(s))
+}
+
+// writeJSONValue 按值的动态类型写入对应的 JSON 字面量
+func writeJSONValue(buf *bytes.Buffer, v interface{}) {
+	switch val := v.(type) {
+	case string:
+		writeJSONString(buf, val)
+	case bool:
+		buf.WriteString(strconv.FormatBool(val))
+	case int64:
+		buf.WriteString(strconv.FormatInt(val, 10))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(val, 'f', -1, 64))
+	case time.Duration:
+		writeJSONString(buf, val.String())
+	case nil:
+		buf.WriteString("null")
+	default:
+		writeJSONString(buf, fmt.Sprintf("%v", val))
+	}
+}
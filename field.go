@@ -0,0 +1,110 @@
+/*
+Copyright 2024 WJQserver Studio. WJQserver Studio 2.0 License.
+*/
+
+package logger
+
+import "time"
+
+// FieldType 标识 Field 中实际存放的值的类型
+type FieldType uint8
+
+// Field 支持的值类型
+const (
+	FieldTypeString FieldType = iota
+	FieldTypeInt
+	FieldTypeInt64
+	FieldTypeFloat64
+	FieldTypeBool
+	FieldTypeDuration
+	FieldTypeTime
+	FieldTypeError
+	FieldTypeAny
+)
+
+// Field 是一个带类型的键值对，用于结构化日志的上下文字段
+//
+// 数值类型统一存放在 Integer/Float 中以避免 interface{} 装箱，
+// 只有 FieldTypeError 和 FieldTypeAny 会落到 Interface 字段上
+type Field struct {
+	Key       string
+	Type      FieldType
+	Integer   int64
+	Float     float64
+	String    string
+	Interface interface{}
+}
+
+// String 构造一个字符串字段
+func String(key, value string) Field {
+	return Field{Key: key, Type: FieldTypeString, String: value}
+}
+
+// Int 构造一个整型字段
+func Int(key string, value int) Field {
+	return Field{Key: key, Type: FieldTypeInt, Integer: int64(value)}
+}
+
+// Int64 构造一个 int64 字段
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Type: FieldTypeInt64, Integer: value}
+}
+
+// Float64 构造一个浮点数字段
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: FieldTypeFloat64, Float: value}
+}
+
+// Bool 构造一个布尔字段
+func Bool(key string, value bool) Field {
+	var i int64
+	if value {
+		i = 1
+	}
+	return Field{Key: key, Type: FieldTypeBool, Integer: i}
+}
+
+// Duration 构造一个 time.Duration 字段
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: FieldTypeDuration, Integer: int64(value)}
+}
+
+// Time 构造一个 time.Time 字段
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Type: FieldTypeTime, Integer: value.UnixNano()}
+}
+
+// Err 构造一个错误字段，键固定为 "error"
+func Err(err error) Field {
+	return Field{Key: "error", Type: FieldTypeError, Interface: err}
+}
+
+// Any 构造一个任意类型的字段，仅在没有专用构造函数时使用，性能不如其它构造函数
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, Type: FieldTypeAny, Interface: value}
+}
+
+// Value 返回该 Field 携带的值，供 Encoder 统一处理
+func (f Field) Value() interface{} {
+	switch f.Type {
+	case FieldTypeString:
+		return f.String
+	case FieldTypeInt, FieldTypeInt64:
+		return f.Integer
+	case FieldTypeFloat64:
+		return f.Float
+	case FieldTypeBool:
+		return f.Integer != 0
+	case FieldTypeDuration:
+		return time.Duration(f.Integer)
+	case FieldTypeTime:
+		return time.Unix(0, f.Integer).UTC()
+	case FieldTypeError:
+		if err, ok := f.Interface.(error); ok && err != nil {
+			return err.Error()
+		}
+		return nil
+	default:
+		return f.Interface
+	}
+}
@@ -5,20 +5,13 @@ Copyright 2024 WJQserver Studio. WJQserver Studio 2.0 License.
 package logger
 
 import (
-	"archive/tar"
-	"compress/gzip"
+	"context"
 	"fmt"
-	"io"
-
-	//"log"
 	"os"
-	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
-
-	"github.com/WJQSERVER-STUDIO/go-utils/log"
 )
 
 // 常量定义
@@ -46,107 +39,209 @@ var logLevelMap = map[string]int{
 	"none":  LevelNone,
 }
 
-// Logger 结构体封装了日志记录器的功能
-type Logger struct {
-	logger       *log.Logger  // 日志记录器实例
-	logFile      *os.File     // 日志文件句柄
+// loggerCore 持有 Logger 的共享可变状态（等级、Sink、调用处配置）
+//
+// Logger.With 返回的子 Logger 与父 Logger 共用同一个 core，
+// 这样在父 Logger 上 AddSink/SetLogLevel 的效果对所有子 Logger 同样生效，
+// 子 Logger 之间的区别只在于各自携带的绑定字段
+type loggerCore struct {
 	logLevel     atomic.Value // 当前日志等级
-	logFileMutex sync.Mutex   // 互斥锁，确保线程安全
-	maxLogSizeMB int64        // 最大日志文件大小（MB）
+	maxLogSizeMB int64        // 最大日志文件大小（MB），供 InitStruct 创建的 FileSink 使用
 	initOnce     sync.Once    // 确保初始化只执行一次
-	droppedLogs  int64        // 统计丢弃的日志数量（未使用）
+
+	sinksMu  sync.RWMutex   // 保护 sinks
+	sinks    []*sinkBinding // 当前挂载的输出目的地
+	fileSink *FileSink      // InitStruct 创建的默认文件 Sink，供 SetRotationPolicy 使用
+
+	callerEnabled int32 // 是否记录调用处信息（0/1，atomic 访问）
+	callerSkip    int32 // resolveCaller 自动跳过本包内部帧之后，再额外跳过的栈帧数，供包装库调整
+
+	asyncMu         sync.Mutex    // 保护 asyncQueue/asyncStop/asyncDone 的（重新）创建
+	asyncEnabled    int32         // 是否已开启异步写入（0/1，atomic 访问）
+	asyncQueue      chan *Entry   // 有界的待写入队列
+	asyncPolicy     atomic.Value  // 队列写满时的处理策略（OverflowPolicy）
+	asyncStop       chan struct{} // 关闭以通知后台写入 goroutine 停止
+	asyncDone       chan struct{} // 后台写入 goroutine 退出后关闭
+	droppedLogs     int64         // 因异步队列写满而被丢弃的日志数量
+	bytesWritten    int64         // 已分发给各 Sink 的日志正文总字节数（近似值）
+	pendingDispatch int64         // 已从队列取出但 dispatch 尚未返回的 Entry 数；Flush 据此判断是否真正写完，而不是只看队列是否已空
+}
+
+// Logger 结构体封装了日志记录器的功能
+type Logger struct {
+	core   *loggerCore
+	fields []Field // Logger.With 绑定的上下文字段，会附加到该 Logger 记录的每一条日志上
 }
 
 // NewLogger 创建一个新的 Logger 实例
 func NewLogger() *Logger {
-	l := &Logger{
-		logLevel:     atomic.Value{}, // 初始化 atomic.Value
-		maxLogSizeMB: 100,            // 默认最大日志大小 100MB
+	core := &loggerCore{
+		maxLogSizeMB: 100, // 默认最大日志大小 100MB
 	}
-	l.logLevel.Store(LevelDump) // 默认日志级别为 LevelDump
-	return l
+	core.logLevel.Store(LevelDump) // 默认日志级别为 LevelDump
+	return &Logger{core: core}
+}
+
+// With 返回一个携带额外绑定字段的子 Logger，该子 Logger 与当前 Logger 共享同一组 Sink 和等级配置
+//
+// 绑定字段会自动附加到通过该子 Logger 记录的每一条结构化日志上，适合携带 request-id、user-id 等上下文
+func (l *Logger) With(fields ...Field) *Logger {
+	merged := make([]Field, 0, len(l.fields)+len(fields))
+	merged = append(merged, l.fields...)
+	merged = append(merged, fields...)
+	return &Logger{core: l.core, fields: merged}
 }
 
 // SetLogLevel 设置日志等级
 func (l *Logger) SetLogLevelStruct(level string) error {
 	level = strings.ToLower(level) // 转换为小写以进行匹配
 	if lvl, ok := logLevelMap[level]; ok {
-		l.logLevel.Store(lvl) // 存储新的日志等级
+		l.core.logLevel.Store(lvl) // 存储新的日志等级
 		return nil
 	}
 	return fmt.Errorf("invalid log level: %s", level) // 返回错误信息
 }
 
-// Init 初始化日志记录器
+// Init 初始化日志记录器，将默认 Logger 挂载到一个文件 Sink 和一个控制台 Sink 上
 func (l *Logger) InitStruct(logFilePath string) error {
 	var initErr error
-	l.initOnce.Do(func() {
-		if err := l.validateLogFilePath(logFilePath); err != nil {
-			initErr = fmt.Errorf("invalid log file path: %w", err)
-			return
-		}
-
-		l.logFileMutex.Lock()
-		defer l.logFileMutex.Unlock()
-
-		var err error
-		l.logFile, err = os.OpenFile(logFilePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	l.core.initOnce.Do(func() {
+		fileSink, err := NewFileSink(logFilePath, int(l.core.maxLogSizeMB))
 		if err != nil {
-			initErr = fmt.Errorf("failed to open log file: %w", err)
+			initErr = err
 			return
 		}
-
-		// 移除标准日志标志，以便手动控制时间格式
-		l.logger = log.New(l.logFile, "", 0)
-		l.logger.SetAsync(4096)
-		go l.monitorLogSize(logFilePath, l.maxLogSizeMB*1024*1024) // 启动日志文件大小监控
+		l.core.fileSink = fileSink
+		l.addSinkLocked(fileSink, LevelDump)
+		l.addSinkLocked(NewConsoleSink(os.Stderr), LevelDump)
 	})
 	return initErr
 }
 
-// validateLogFilePath 验证日志文件路径的有效性
-func (l *Logger) validateLogFilePath(path string) error {
-	dir := filepath.Dir(path) // 获取目录路径
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		return fmt.Errorf("directory does not exist: %s", dir) // 返回目录不存在的错误
+// SetRotationPolicy 配置 InitStruct 创建的默认文件 Sink 的滚动与归档清理策略
+//
+// 必须在 InitStruct 成功之后调用，否则返回错误
+func (l *Logger) SetRotationPolicyStruct(policy RotationPolicy) error {
+	if l.core.fileSink == nil {
+		return fmt.Errorf("logger: file sink not initialized, call InitStruct first")
 	}
+	l.core.fileSink.SetRotationPolicy(policy)
 	return nil
 }
 
-// SetMaxLogSizeMB 设置最大日志文件大小（MB）
+// SetMaxLogSizeMB 设置最大日志文件大小（MB），需在 InitStruct 之前调用才会生效
 func (l *Logger) SetMaxLogSizeMBStruct(maxSizeMB int) {
-	l.maxLogSizeMB = int64(maxSizeMB) // 更新最大日志大小
+	l.core.maxLogSizeMB = int64(maxSizeMB) // 更新最大日志大小
+}
+
+// AddSink 挂载一个新的输出目的地，minLevel 为该 Sink 自己的最低日志等级阈值
+func (l *Logger) AddSink(sink Sink, minLevel int) {
+	l.core.sinksMu.Lock()
+	defer l.core.sinksMu.Unlock()
+	l.addSinkLocked(sink, minLevel)
+}
+
+// addSinkLocked 在持有 l.core.sinksMu 的前提下追加一个 Sink
+func (l *Logger) addSinkLocked(sink Sink, minLevel int) {
+	l.core.sinks = append(l.core.sinks, &sinkBinding{sink: sink, minLevel: minLevel})
+}
+
+// SetCallerSkip 设置在默认栈帧深度基础上额外跳过的帧数
+//
+// 当业务代码在自己的日志封装函数里调用本包时，默认的栈帧深度会指向封装函数
+// 而不是真正的调用处，此时可通过该方法加上封装函数引入的额外帧数
+func (l *Logger) SetCallerSkip(skip int) {
+	atomic.StoreInt32(&l.core.callerSkip, int32(skip))
+}
+
+// SetCallerEnabled 开启或关闭调用处文件/行号/函数名的记录
+//
+// runtime.Caller 和 FuncForPC 并非零成本，默认关闭；仅在确实需要写入调用处信息时才解析
+func (l *Logger) SetCallerEnabled(enabled bool) {
+	if enabled {
+		atomic.StoreInt32(&l.core.callerEnabled, 1)
+	} else {
+		atomic.StoreInt32(&l.core.callerEnabled, 0)
+	}
+}
+
+// RemoveSink 移除一个之前通过 AddSink 挂载的输出目的地并关闭它
+func (l *Logger) RemoveSink(sink Sink) error {
+	l.core.sinksMu.Lock()
+	defer l.core.sinksMu.Unlock()
+
+	for i, b := range l.core.sinks {
+		if b.sink == sink {
+			l.core.sinks = append(l.core.sinks[:i], l.core.sinks[i+1:]...)
+			return b.sink.Close()
+		}
+	}
+	return fmt.Errorf("sink not found")
 }
 
 // Log 记录日志
 func (l *Logger) LogStruct(level int, msg string) {
-	if level < l.logLevel.Load().(int) {
+	l.logWithFields(level, msg, nil)
+}
+
+// LogKVStruct 记录一条带结构化字段的日志，字段会和 Logger.With 绑定的字段合并后交给各 Sink 的 Encoder 渲染
+func (l *Logger) LogKVStruct(level int, msg string, fields ...Field) {
+	l.logWithFields(level, msg, fields)
+}
+
+// logWithFields 是 LogStruct/LogKVStruct 共用的实现：按等级过滤、解析调用处、合并字段、分发给各 Sink
+func (l *Logger) logWithFields(level int, msg string, extra []Field) {
+	if level < l.core.logLevel.Load().(int) {
 		return // 如果当前日志等级低于设定等级，则不记录
 	}
 
-	logPrefix := ""
-	switch level {
-	case LevelDump:
-		logPrefix = "[DUMP] "
-	case LevelDebug:
-		logPrefix = "[DEBUG] "
-	case LevelInfo:
-		logPrefix = "[INFO] "
-	case LevelWarn:
-		logPrefix = "[WARNING] "
-	case LevelError:
-		logPrefix = "[ERROR] "
+	entry := &Entry{
+		Time:    time.Now(),
+		Level:   level,
+		Message: msg,
+	}
+
+	if len(l.fields) > 0 || len(extra) > 0 {
+		entry.Fields = make([]Field, 0, len(l.fields)+len(extra))
+		entry.Fields = append(entry.Fields, l.fields...)
+		entry.Fields = append(entry.Fields, extra...)
+	}
+
+	if atomic.LoadInt32(&l.core.callerEnabled) == 1 {
+		extraSkip := int(atomic.LoadInt32(&l.core.callerSkip))
+		if file, line, fn, ok := resolveCaller(extraSkip); ok {
+			entry.CallerFile = file
+			entry.CallerLine = line
+			entry.CallerFunc = fn
+		}
 	}
 
-	l.logFileMutex.Lock()
-	defer l.logFileMutex.Unlock()
-	// 手动格式化时间并记录日志
-	l.logger.Printf("%s - %s%s", time.Now().Format(timeFormat), logPrefix, msg)
+	if atomic.LoadInt32(&l.core.asyncEnabled) == 1 {
+		l.core.enqueue(entry)
+		return
+	}
+	l.core.dispatch(entry)
+}
+
+// dispatch 把 entry 分发给所有等级达标的 Sink，这是同步路径和异步写入 goroutine 共用的落地逻辑
+func (core *loggerCore) dispatch(entry *Entry) {
+	atomic.AddInt64(&core.bytesWritten, int64(len(entry.Message)))
+
+	core.sinksMu.RLock()
+	defer core.sinksMu.RUnlock()
+	for _, b := range core.sinks {
+		if entry.Level < b.minLevel {
+			continue
+		}
+		if err := b.sink.Write(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: sink write failed: %v\n", err)
+		}
+	}
 }
 
 // Logf 格式化日志记录
 func (l *Logger) LogfStruct(level int, format string, args ...interface{}) {
-	l.LogStruct(level, fmt.Sprintf(format, args...)) // 调用 LogStruct 记录格式化日志
+	l.logWithFields(level, fmt.Sprintf(format, args...), nil)
 }
 
 // LogDump 快捷日志方法
@@ -174,127 +269,51 @@ func (l *Logger) LogErrorStruct(format string, args ...interface{}) {
 	l.LogfStruct(LevelError, format, args...) // 记录 ERROR 级别日志
 }
 
-// Close 关闭日志系统
-func (l *Logger) CloseStruct() {
-	l.logFileMutex.Lock()
-	defer l.logFileMutex.Unlock()
-	if l.logFile != nil {
-		if err := l.logFile.Close(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error closing log file: %v\n", err) // 输出关闭日志文件时的错误
-		}
-		l.logFile = nil // 确保在关闭后将 logFile 设置为 nil
-	}
+// LogDumpKV 结构化日志快捷方法
+func (l *Logger) LogDumpKVStruct(msg string, fields ...Field) {
+	l.LogKVStruct(LevelDump, msg, fields...)
 }
 
-// monitorLogSize 定期检查日志文件大小
-func (l *Logger) monitorLogSize(logFilePath string, maxBytes int64) {
-	// 预检测一次
-	go func() {
-		time.Sleep(30 * time.Second)
-		l.logFileMutex.Lock()
-		info, err := l.logFile.Stat() // 获取日志文件信息
-		l.logFileMutex.Unlock()
-
-		if err == nil && info.Size() > maxBytes {
-			if err := l.rotateLogFile(logFilePath); err != nil {
-				l.LogErrorStruct("Log rotation failed: %v", err) // 记录日志轮转失败的错误
-			}
-		}
-	}()
-
-	ticker := time.NewTicker(15 * time.Minute) // 每 15 分钟检查一次
-	defer ticker.Stop()
-
-	for range ticker.C {
-		l.logFileMutex.Lock()
-		info, err := l.logFile.Stat() // 获取日志文件信息
-		l.logFileMutex.Unlock()
-
-		if err == nil && info.Size() > maxBytes {
-			if err := l.rotateLogFile(logFilePath); err != nil {
-				l.LogErrorStruct("Log rotation failed: %v", err) // 记录日志轮转失败的错误
-			}
-		}
-	}
+// LogDebugKV 结构化日志快捷方法
+func (l *Logger) LogDebugKVStruct(msg string, fields ...Field) {
+	l.LogKVStruct(LevelDebug, msg, fields...)
 }
 
-// rotateLogFile 轮转日志文件
-func (l *Logger) rotateLogFile(logFilePath string) error {
-	l.logFileMutex.Lock()
-	defer l.logFileMutex.Unlock()
-
-	if l.logFile != nil {
-		if err := l.logFile.Close(); err != nil {
-			return fmt.Errorf("error closing log file: %w", err) // 返回关闭日志文件时的错误
-		}
-	}
-
-	backupPath := fmt.Sprintf("%s.%s", logFilePath, time.Now().Format("20060102-150405")) // 生成备份文件名
-	if err := os.Rename(logFilePath, backupPath); err != nil {
-		return fmt.Errorf("error renaming log file: %w", err) // 返回重命名日志文件时的错误
-	}
-
-	newFile, err := os.OpenFile(logFilePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
-	if err != nil {
-		return fmt.Errorf("error creating new log file: %w", err) // 返回创建新日志文件时的错误
-	}
-	l.logFile = newFile
-	l.logger.SetOutput(l.logFile) // 更新 logger 的输出目标
-
-	go func() {
-		if err := l.compressLog(backupPath); err != nil {
-			l.LogErrorStruct("Compression failed: %v", err) // 记录压缩失败的错误
-		}
-		if err := os.Remove(backupPath); err != nil {
-			l.LogErrorStruct("Failed to remove backup file: %v", err) // 记录删除备份文件失败的错误
-			fmt.Printf("Failed to remove backup file: %v\n", err)
-		}
-	}()
-
-	return nil
+// LogInfoKV 结构化日志快捷方法
+func (l *Logger) LogInfoKVStruct(msg string, fields ...Field) {
+	l.LogKVStruct(LevelInfo, msg, fields...)
 }
 
-// compressLog 压缩日志文件
-func (l *Logger) compressLog(srcPath string) error {
-	srcFile, err := os.Open(srcPath) // 打开源日志文件
-	if err != nil {
-		return err
-	}
-	defer srcFile.Close()
-
-	dstFile, err := os.Create(srcPath + ".tar.gz") // 创建压缩文件
-	if err != nil {
-		return err
-	}
-	defer dstFile.Close()
-
-	gzWriter := gzip.NewWriter(dstFile) // 创建 gzip 写入器
-	defer gzWriter.Close()
-
-	tarWriter := tar.NewWriter(gzWriter) // 创建 tar 写入器
-	defer tarWriter.Close()
+// LogWarningKV 结构化日志快捷方法
+func (l *Logger) LogWarningKVStruct(msg string, fields ...Field) {
+	l.LogKVStruct(LevelWarn, msg, fields...)
+}
 
-	info, err := srcFile.Stat() // 获取源文件信息
-	if err != nil {
-		return err
-	}
+// LogErrorKV 结构化日志快捷方法
+func (l *Logger) LogErrorKVStruct(msg string, fields ...Field) {
+	l.LogKVStruct(LevelError, msg, fields...)
+}
 
-	header := &tar.Header{
-		Name:    filepath.Base(srcPath), // 设置 tar 头部信息
-		Size:    info.Size(),
-		Mode:    int64(info.Mode()),
-		ModTime: info.ModTime(),
-	}
+// Close 关闭日志系统：先排空异步队列（如果启用），再依次关闭所有已挂载的 Sink
+func (l *Logger) CloseStruct() {
+	core := l.core
 
-	if err := tarWriter.WriteHeader(header); err != nil {
-		return err // 写入 tar 头部时的错误
+	core.asyncMu.Lock()
+	if core.asyncQueue != nil {
+		close(core.asyncStop)
+		<-core.asyncDone
+		core.asyncQueue = nil
 	}
+	core.asyncMu.Unlock()
 
-	if _, err := io.Copy(tarWriter, srcFile); err != nil {
-		return err // 复制文件内容时的错误
+	core.sinksMu.Lock()
+	defer core.sinksMu.Unlock()
+	for _, b := range core.sinks {
+		if err := b.sink.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error closing sink: %v\n", err) // 输出关闭 Sink 时的错误
+		}
 	}
-
-	return nil
+	core.sinks = nil
 }
 
 // 全局 Logger 实例
@@ -324,6 +343,51 @@ func SetMaxLogSizeMB(maxSizeMB int) {
 	defaultLogger.SetMaxLogSizeMBStruct(maxSizeMB) // 调用内部的 SetMaxLogSizeMBStruct
 }
 
+// SetRotationPolicy 配置默认 Logger 的文件滚动与归档清理策略，需在 Init 之后调用
+func SetRotationPolicy(policy RotationPolicy) error {
+	return defaultLogger.SetRotationPolicyStruct(policy)
+}
+
+// SetAsync 为默认 Logger 开启异步写入
+func SetAsync(bufferSize int, policy OverflowPolicy) {
+	defaultLogger.SetAsync(bufferSize, policy)
+}
+
+// Stats 返回默认 Logger 的异步管线统计信息
+func Stats() LoggerStats {
+	return defaultLogger.Stats()
+}
+
+// Flush 阻塞直到默认 Logger 的异步队列被完全消费，或 ctx 被取消/超时
+func Flush(ctx context.Context) error {
+	return defaultLogger.Flush(ctx)
+}
+
+// SetCallerSkip 为默认 Logger 设置额外跳过的栈帧数
+func SetCallerSkip(skip int) {
+	defaultLogger.SetCallerSkip(skip)
+}
+
+// SetCallerEnabled 开启或关闭默认 Logger 的调用处信息记录
+func SetCallerEnabled(enabled bool) {
+	defaultLogger.SetCallerEnabled(enabled)
+}
+
+// AddSink 为默认 Logger 挂载一个输出目的地
+func AddSink(sink Sink, minLevel int) {
+	defaultLogger.AddSink(sink, minLevel)
+}
+
+// RemoveSink 从默认 Logger 移除一个输出目的地
+func RemoveSink(sink Sink) error {
+	return defaultLogger.RemoveSink(sink)
+}
+
+// With 返回携带额外绑定字段的子 Logger，基于默认 Logger
+func With(fields ...Field) *Logger {
+	return defaultLogger.With(fields...)
+}
+
 // 关闭日志系统
 func Close() {
 	defaultLogger.CloseStruct() // 调用内部的 CloseStruct
@@ -359,3 +423,24 @@ func LogWarning(format string, args ...interface{}) {
 func LogError(format string, args ...interface{}) {
 	defaultLogger.LogErrorStruct(format, args...) // 调用内部的 LogErrorStruct
 }
+
+// 结构化日志函数，使用原有的函数命名风格
+func LogDumpKV(msg string, fields ...Field) {
+	defaultLogger.LogDumpKVStruct(msg, fields...)
+}
+
+func LogDebugKV(msg string, fields ...Field) {
+	defaultLogger.LogDebugKVStruct(msg, fields...)
+}
+
+func LogInfoKV(msg string, fields ...Field) {
+	defaultLogger.LogInfoKVStruct(msg, fields...)
+}
+
+func LogWarningKV(msg string, fields ...Field) {
+	defaultLogger.LogWarningKVStruct(msg, fields...)
+}
+
+func LogErrorKV(msg string, fields ...Field) {
+	defaultLogger.LogErrorKVStruct(msg, fields...)
+}
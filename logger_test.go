@@ -1,9 +1,34 @@
 package logger
 
 import (
+	"sync"
 	"testing"
 )
 
+// captureSink 是仅供测试使用的 Sink，记录收到的全部 Entry
+type captureSink struct {
+	mu      sync.Mutex
+	entries []*Entry
+}
+
+func (s *captureSink) Write(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+func (s *captureSink) Close() error { return nil }
+
+func (s *captureSink) last() *Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) == 0 {
+		return nil
+	}
+	return s.entries[len(s.entries)-1]
+}
+
 func BenchmarkLogInfo(b *testing.B) {
 	// 初始化日志记录器
 	err := Init("test.log", 10) // 设置日志文件路径和最大大小
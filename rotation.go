@@ -0,0 +1,43 @@
+/*
+Copyright 2024 WJQserver Studio. WJQserver Studio 2.0 License.
+*/
+
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RotationPolicy 描述 FileSink 的滚动与归档清理策略
+type RotationPolicy struct {
+	MaxSizeMB  int    // 触发滚动的文件大小上限（MB），0 表示不按大小滚动
+	MaxAgeDays int    // 归档文件最长保留天数，超期的归档会被清理；0 表示不按时间清理
+	MaxBackups int    // 最多保留的归档文件数量，超出的部分从最旧的开始清理；0 表示不限制
+	RotateAt   string // 每天定时滚动的时间点，格式 "HH:MM"（24 小时制），留空表示不启用定时滚动
+	Compress   bool   // 归档时是否压缩为 .tar.gz
+}
+
+// nextRotateTime 计算从 from 开始下一次触发 hhmm（"HH:MM"）定时滚动的时间点
+func nextRotateTime(hhmm string, from time.Time) (time.Time, error) {
+	parts := strings.Split(hhmm, ":")
+	if len(parts) != 2 {
+		return time.Time{}, fmt.Errorf("invalid RotateAt %q, want \"HH:MM\"", hhmm)
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid RotateAt %q: %w", hhmm, err)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid RotateAt %q: %w", hhmm, err)
+	}
+
+	next := time.Date(from.Year(), from.Month(), from.Day(), hour, minute, 0, 0, from.Location())
+	if !next.After(from) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next, nil
+}
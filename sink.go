@@ -0,0 +1,69 @@
+/*
+Copyright 2024 WJQserver Studio. WJQserver Studio 2.0 License.
+*/
+
+package logger
+
+import (
+	"fmt"
+	"time"
+)
+
+// Entry 表示一条待输出的日志记录，由 Logger 构造后分发给各个 Sink
+type Entry struct {
+	Time    time.Time // 记录时间
+	Level   int       // 日志等级
+	Message string    // 已格式化的日志正文
+
+	CallerFile string // 调用处文件名（不含目录），未启用 caller 时为空
+	CallerLine int    // 调用处行号
+	CallerFunc string // 调用处所在函数名
+
+	Fields []Field // 结构化字段，包含 Logger.With 绑定的上下文字段
+}
+
+// Sink 是日志输出目的地的抽象，Logger 会将每条记录分发给它关心的 Sink
+//
+// Write 必须是并发安全的，因为 Logger 可能从多个 goroutine 同时调用；
+// Close 用于在 Logger 关闭或 Sink 被移除时释放底层资源（文件句柄、连接等）
+type Sink interface {
+	Write(entry *Entry) error
+	Close() error
+}
+
+// sinkBinding 将一个 Sink 与它自己的最低日志等级阈值绑定在一起
+type sinkBinding struct {
+	sink     Sink
+	minLevel int
+}
+
+// levelPrefix 返回日志等级对应的文本前缀，例如 "[INFO] "
+func levelPrefix(level int) string {
+	switch level {
+	case LevelDump:
+		return "[DUMP] "
+	case LevelDebug:
+		return "[DEBUG] "
+	case LevelInfo:
+		return "[INFO] "
+	case LevelWarn:
+		return "[WARNING] "
+	case LevelError:
+		return "[ERROR] "
+	default:
+		return ""
+	}
+}
+
+// formatTextEntry 将 entry 渲染为现有的人类可读文本格式：
+// "2006-01-02T15:04:05Z07:00 - [INFO] msg"，
+// 若 entry 携带调用处信息，则渲染为
+// "2006-01-02T15:04:05Z07:00 [INFO] file.go:42 pkg.Func - msg"
+func formatTextEntry(entry *Entry) string {
+	prefix := levelPrefix(entry.Level)
+	if entry.CallerFile != "" {
+		return fmt.Sprintf("%s %s%s:%d %s - %s",
+			entry.Time.Format(timeFormat), prefix, entry.CallerFile, entry.CallerLine, entry.CallerFunc, entry.Message)
+	}
+	return fmt.Sprintf("%s - %s%s", entry.Time.Format(timeFormat), prefix, entry.Message)
+}
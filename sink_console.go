@@ -0,0 +1,88 @@
+/*
+Copyright 2024 WJQserver Studio. WJQserver Studio 2.0 License.
+*/
+
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// 各日志等级在终端上的 ANSI 颜色
+var consoleLevelColors = map[int]string{
+	LevelDump:  "\x1b[90m", // 灰色
+	LevelDebug: "\x1b[36m", // 青色
+	LevelInfo:  "\x1b[32m", // 绿色
+	LevelWarn:  "\x1b[33m", // 黄色
+	LevelError: "\x1b[31m", // 红色
+}
+
+const consoleColorReset = "\x1b[0m"
+
+// ConsoleSink 将日志写入到给定的 *os.File（通常是 os.Stdout 或 os.Stderr），
+// 当目标是终端时会按日志等级着色
+type ConsoleSink struct {
+	out *os.File
+
+	mu       sync.Mutex // 保护 colorize/encoder，Write 可能与 SetColorize/SetEncoder 并发执行
+	colorize bool
+	encoder  Encoder
+}
+
+// NewConsoleSink 创建一个写入 out 的 ConsoleSink
+//
+// 是否着色由 out 是否为终端自动判断，可用 SetColorize 强制开启或关闭；
+// 默认使用 TextEncoder，可用 SetEncoder 换成 JSONEncoder/LogfmtEncoder
+func NewConsoleSink(out *os.File) *ConsoleSink {
+	return &ConsoleSink{
+		out:      out,
+		colorize: isTerminal(out),
+		encoder:  TextEncoder{},
+	}
+}
+
+// SetColorize 强制开启或关闭颜色输出，忽略自动检测结果
+func (s *ConsoleSink) SetColorize(enabled bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.colorize = enabled
+}
+
+// SetEncoder 替换该 Sink 使用的 Encoder
+func (s *ConsoleSink) SetEncoder(encoder Encoder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.encoder = encoder
+}
+
+// Write 实现 Sink 接口
+func (s *ConsoleSink) Write(entry *Entry) error {
+	s.mu.Lock()
+	colorize, encoder := s.colorize, s.encoder
+	s.mu.Unlock()
+
+	line := string(encoder.Encode(entry))
+	if !colorize {
+		_, err := fmt.Fprintln(s.out, line)
+		return err
+	}
+	color := consoleLevelColors[entry.Level]
+	_, err := fmt.Fprintln(s.out, color+line+consoleColorReset)
+	return err
+}
+
+// Close 实现 Sink 接口，ConsoleSink 不拥有底层文件句柄，因此无需关闭
+func (s *ConsoleSink) Close() error {
+	return nil
+}
+
+// isTerminal 判断 f 是否连接到一个字符设备（终端）
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return (info.Mode() & os.ModeCharDevice) != 0
+}
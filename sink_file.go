@@ -0,0 +1,362 @@
+/*
+Copyright 2024 WJQserver Studio. WJQserver Studio 2.0 License.
+*/
+
+package logger
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/WJQSERVER-STUDIO/go-utils/log"
+)
+
+// FileSink 将日志写入本地文件，并按 RotationPolicy 滚动、压缩、清理归档
+//
+// 这是原先内置在 Logger 中的落盘实现，现在作为一个标准 Sink 独立出来，
+// 使得 Logger 可以同时挂载文件、控制台等多种输出目的地
+type FileSink struct {
+	path    string
+	logFile *os.File
+	logger  *log.Logger
+	mu      sync.Mutex
+	encoder Encoder
+
+	policy atomic.Value // RotationPolicy
+
+	sweepOnce    sync.Once
+	scheduleMu   sync.Mutex
+	scheduleStop chan struct{} // 关闭以取消当前的定时滚动 goroutine
+}
+
+// NewFileSink 创建一个写入 path 的 FileSink，maxLogSizeMB 为触发滚动的文件大小上限
+//
+// 更完整的滚动/保留策略（按时间清理、按数量清理、每日定时滚动、压缩开关）
+// 可在创建后通过 SetRotationPolicy 配置
+func NewFileSink(path string, maxLogSizeMB int) (*FileSink, error) {
+	if err := validateLogFilePath(path); err != nil {
+		return nil, fmt.Errorf("invalid log file path: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	s := &FileSink{
+		path:    path,
+		logFile: f,
+		encoder: TextEncoder{},
+	}
+	s.policy.Store(RotationPolicy{MaxSizeMB: maxLogSizeMB, Compress: true})
+
+	// 移除标准日志标志，以便手动控制时间格式
+	s.logger = log.New(s.logFile, "", 0)
+	s.logger.SetAsync(4096)
+
+	go s.monitorLogSize()
+
+	return s, nil
+}
+
+// validateLogFilePath 验证日志文件路径的有效性
+func validateLogFilePath(path string) error {
+	dir := filepath.Dir(path)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", dir)
+	}
+	return nil
+}
+
+// SetEncoder 替换该 Sink 使用的 Encoder
+func (s *FileSink) SetEncoder(encoder Encoder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.encoder = encoder
+}
+
+// SetRotationPolicy 配置该 FileSink 的滚动与归档清理策略
+//
+// 按大小滚动和按时间/数量清理归档会立即生效；RotateAt 非空时会启动一个每日定时滚动的
+// 后台 goroutine，再次调用会取消上一个定时滚动并按新的 RotateAt 重新安排
+func (s *FileSink) SetRotationPolicy(policy RotationPolicy) {
+	s.policy.Store(policy)
+	s.sweepOnce.Do(func() { go s.runSweep() })
+
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+	if s.scheduleStop != nil {
+		close(s.scheduleStop)
+		s.scheduleStop = nil
+	}
+	if policy.RotateAt != "" {
+		stop := make(chan struct{})
+		s.scheduleStop = stop
+		go s.runScheduledRotation(policy.RotateAt, stop)
+	}
+}
+
+// Write 实现 Sink 接口
+//
+// 用 Output 而不是 Printf 写入：Encoder 已经把 entry 渲染成了最终字节，
+// Printf 还会对它再跑一遍 fmt 格式化（扫描 "%s" 格式串），这里没有必要；
+// Output 跳过格式化直接把这份字节串交给底层（仍然经由 s.logger 的异步
+// 缓冲管线，而不是绕开它直接写 s.logFile）
+func (s *FileSink) Write(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger.Output(2, string(s.encoder.Encode(entry)))
+	return nil
+}
+
+// Close 实现 Sink 接口，关闭底层文件句柄并停止定时滚动
+func (s *FileSink) Close() error {
+	s.scheduleMu.Lock()
+	if s.scheduleStop != nil {
+		close(s.scheduleStop)
+		s.scheduleStop = nil
+	}
+	s.scheduleMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.logFile != nil {
+		if err := s.logFile.Close(); err != nil {
+			return err
+		}
+		s.logFile = nil
+	}
+	return nil
+}
+
+// monitorLogSize 定期检查日志文件大小
+func (s *FileSink) monitorLogSize() {
+	// 预检测一次
+	go func() {
+		time.Sleep(30 * time.Second)
+		s.checkAndRotate()
+	}()
+
+	ticker := time.NewTicker(15 * time.Minute) // 每 15 分钟检查一次
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.checkAndRotate()
+	}
+}
+
+// checkAndRotate 在文件超出大小限制时触发滚动
+func (s *FileSink) checkAndRotate() {
+	maxSizeMB := s.policy.Load().(RotationPolicy).MaxSizeMB
+	if maxSizeMB <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	info, err := s.logFile.Stat()
+	s.mu.Unlock()
+
+	if err == nil && info.Size() > int64(maxSizeMB)*1024*1024 {
+		if err := s.rotateLogFile(); err != nil {
+			fmt.Fprintf(os.Stderr, "Log rotation failed: %v\n", err)
+		}
+	}
+}
+
+// runScheduledRotation 每天在 hhmm 指定的时间点触发一次滚动，直到 stop 被关闭
+func (s *FileSink) runScheduledRotation(hhmm string, stop chan struct{}) {
+	for {
+		next, err := nextRotateTime(hhmm, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Scheduled log rotation disabled: %v\n", err)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+			if err := s.rotateLogFile(); err != nil {
+				fmt.Fprintf(os.Stderr, "Scheduled log rotation failed: %v\n", err)
+			}
+		case <-stop:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// rotateLogFile 轮转日志文件
+func (s *FileSink) rotateLogFile() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.logFile != nil {
+		if err := s.logFile.Close(); err != nil {
+			return fmt.Errorf("error closing log file: %w", err)
+		}
+	}
+
+	backupPath := fmt.Sprintf("%s.%s", s.path, time.Now().Format("20060102-150405"))
+	if err := os.Rename(s.path, backupPath); err != nil {
+		return fmt.Errorf("error renaming log file: %w", err)
+	}
+
+	newFile, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return fmt.Errorf("error creating new log file: %w", err)
+	}
+	s.logFile = newFile
+	s.logger.SetOutput(s.logFile)
+
+	if s.policy.Load().(RotationPolicy).Compress {
+		go func() {
+			if err := compressLog(backupPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Compression failed: %v\n", err)
+				return
+			}
+			if err := os.Remove(backupPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to remove backup file: %v\n", err)
+			}
+		}()
+	}
+
+	return nil
+}
+
+// compressLog 压缩日志文件
+func compressLog(srcPath string) error {
+	srcFile, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.Create(srcPath + ".tar.gz")
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+
+	gzWriter := gzip.NewWriter(dstFile)
+	defer gzWriter.Close()
+
+	tarWriter := tar.NewWriter(gzWriter)
+	defer tarWriter.Close()
+
+	info, err := srcFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	header := &tar.Header{
+		Name:    filepath.Base(srcPath),
+		Size:    info.Size(),
+		Mode:    int64(info.Mode()),
+		ModTime: info.ModTime(),
+	}
+
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(tarWriter, srcFile); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// archivedFile 是归档扫描时记录的一个备份文件及其嵌入的时间戳
+type archivedFile struct {
+	path string
+	ts   time.Time
+}
+
+// runSweep 周期性地按 MaxAgeDays/MaxBackups 清理过期或超量的归档文件
+func (s *FileSink) runSweep() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	s.sweepArchives()
+	for range ticker.C {
+		s.sweepArchives()
+	}
+}
+
+// sweepArchives 枚举 <path>.* 及 <path>.*.tar.gz 归档，按策略清理
+func (s *FileSink) sweepArchives() {
+	policy := s.policy.Load().(RotationPolicy)
+	if policy.MaxAgeDays <= 0 && policy.MaxBackups <= 0 {
+		return
+	}
+
+	archives, err := s.listArchives()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Log retention sweep failed: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	var kept []archivedFile
+	for _, a := range archives {
+		if policy.MaxAgeDays > 0 && now.Sub(a.ts) > time.Duration(policy.MaxAgeDays)*24*time.Hour {
+			if err := os.Remove(a.path); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to prune expired archive %s: %v\n", a.path, err)
+			}
+			continue
+		}
+		kept = append(kept, a)
+	}
+
+	if policy.MaxBackups > 0 && len(kept) > policy.MaxBackups {
+		excess := kept[:len(kept)-policy.MaxBackups]
+		for _, a := range excess {
+			if err := os.Remove(a.path); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to prune excess archive %s: %v\n", a.path, err)
+			}
+		}
+	}
+}
+
+// listArchives 枚举日志文件所在目录下的归档文件，按时间戳升序（最旧的在前）排列
+func (s *FileSink) listArchives() ([]archivedFile, error) {
+	dir := filepath.Dir(s.path)
+	base := filepath.Base(s.path)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var archives []archivedFile
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		if !strings.HasPrefix(name, base+".") {
+			continue
+		}
+		suffix := strings.TrimSuffix(strings.TrimPrefix(name, base+"."), ".tar.gz")
+		// rotateLogFile 用 time.Now().Format 按本地时区生成这个后缀，这里必须用
+		// 同样的时区解析，否则在非 UTC 的机器上 time.Parse 默认按 UTC 解析会带来
+		// 时区偏移量的误差，导致 sweepArchives 按 MaxAgeDays 清理时算错年龄
+		ts, err := time.ParseInLocation("20060102-150405", suffix, time.Local)
+		if err != nil {
+			continue // 不是本 Sink 生成的归档文件，跳过
+		}
+		archives = append(archives, archivedFile{path: filepath.Join(dir, name), ts: ts})
+	}
+
+	sort.Slice(archives, func(i, j int) bool { return archives[i].ts.Before(archives[j].ts) })
+	return archives, nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 WJQserver Studio. WJQserver Studio 2.0 License.
+*/
+
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// NetworkSink 将日志以原始 TCP 流或 HTTP POST 请求的形式发往远端采集服务，
+// 连接断开时按指数退避自动重连
+//
+// 注意：tcp 模式下，远端持续不可达时 Write 会同步阻塞在重连退避上，最坏情况下
+// （minBack=100ms 翻倍到 maxBack=30s）累计耗时可达数十秒。Logger 的异步写入
+// goroutine 是单线程依次把每条 Entry 分发给所有 Sink 的（见 loggerCore.dispatch），
+// 所以这段阻塞期间其它 Sink、乃至队列里排在后面的日志都会被一起拖慢；Write 本身
+// 不会无限阻塞，但调用方应当清楚这个 Sink 在远端故障时不是"非阻塞"的
+type NetworkSink struct {
+	mode string // "tcp" 或 "http"
+	addr string // tcp 模式下的 "host:port"，http 模式下的请求 URL
+
+	mu      sync.Mutex    // 保护 conn/encoder 字段本身的读写；临界区只做字段存取，不做网络 IO
+	conn    net.Conn      // 仅 tcp 模式使用
+	client  *http.Client  // 仅 http 模式使用
+	minBack time.Duration // 重连起始退避时间
+	maxBack time.Duration // 重连最大退避时间
+	encoder Encoder
+
+	// reconnectMu 序列化 reconnect 的退避循环本身，与 mu 分开是为了不在重连
+	// 耗时的这段时间里占着 mu——否则 SetEncoder/Close 这类只需要读写字段的
+	// 调用也会被无谓地一起卡住
+	reconnectMu sync.Mutex
+}
+
+// SetEncoder 替换该 Sink 使用的 Encoder
+func (s *NetworkSink) SetEncoder(encoder Encoder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.encoder = encoder
+}
+
+// NewNetworkTCPSink 创建一个以原始 TCP 连接发送日志的 NetworkSink
+func NewNetworkTCPSink(addr string) (*NetworkSink, error) {
+	s := &NetworkSink{
+		mode:    "tcp",
+		addr:    addr,
+		minBack: 100 * time.Millisecond,
+		maxBack: 30 * time.Second,
+		encoder: TextEncoder{},
+	}
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial network sink: %w", err)
+	}
+	s.conn = conn
+	return s, nil
+}
+
+// NewNetworkHTTPSink 创建一个以 HTTP POST 发送日志的 NetworkSink，url 为采集端点
+func NewNetworkHTTPSink(url string) *NetworkSink {
+	return &NetworkSink{
+		mode:    "http",
+		addr:    url,
+		client:  &http.Client{Timeout: 5 * time.Second},
+		minBack: 100 * time.Millisecond,
+		maxBack: 30 * time.Second,
+		encoder: TextEncoder{},
+	}
+}
+
+// Write 实现 Sink 接口
+func (s *NetworkSink) Write(entry *Entry) error {
+	s.mu.Lock()
+	encoder := s.encoder
+	s.mu.Unlock()
+
+	line := string(encoder.Encode(entry))
+	if s.mode == "http" {
+		return s.postHTTP(line)
+	}
+	return s.writeTCP(line)
+}
+
+// writeTCP 写入一行日志，失败时按退避策略重连后重试一次
+//
+// 只在读写 s.conn 字段时短暂持有 s.mu，真正耗时的重连退避交给 reconnect
+// 去处理（它不持有 s.mu），但 writeTCP/Write 本身依然会同步阻塞到重连
+// 结束为止——见 NetworkSink 的文档
+func (s *NetworkSink) writeTCP(line string) error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+
+	if conn != nil {
+		if _, err := fmt.Fprintln(conn, line); err == nil {
+			return nil
+		}
+	}
+
+	newConn, err := s.reconnect(conn)
+	if err != nil {
+		return fmt.Errorf("network sink reconnect failed: %w", err)
+	}
+	_, err = fmt.Fprintln(newConn, line)
+	return err
+}
+
+// reconnect 按指数退避重新建立 TCP 连接；broken 是调用方观察到已经失效的连接
+// （可能为 nil）
+//
+// 退避循环本身只持有 reconnectMu，不持有 s.mu：多个并发的 writeTCP 调用在
+// remote 不可达时会排队等 reconnectMu，但不会卡住只需要读写 s.conn/s.encoder
+// 的其它调用（SetEncoder、Close）。轮到某个 goroutine 执行时，如果
+// s.conn 已经不再是它观察到的 broken（说明前一个 goroutine已经重连成功），
+// 直接复用新连接，不会重复拨号
+func (s *NetworkSink) reconnect(broken net.Conn) (net.Conn, error) {
+	s.reconnectMu.Lock()
+	defer s.reconnectMu.Unlock()
+
+	s.mu.Lock()
+	current := s.conn
+	s.mu.Unlock()
+	if current != nil && current != broken {
+		return current, nil
+	}
+	if current != nil {
+		current.Close()
+	}
+
+	backoff := s.minBack
+	var lastErr error
+	for backoff <= s.maxBack {
+		conn, err := net.DialTimeout("tcp", s.addr, 5*time.Second)
+		if err == nil {
+			s.mu.Lock()
+			s.conn = conn
+			s.mu.Unlock()
+			return conn, nil
+		}
+		lastErr = err
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	s.mu.Lock()
+	s.conn = nil
+	s.mu.Unlock()
+	return nil, fmt.Errorf("giving up after backoff: %w", lastErr)
+}
+
+// postHTTP 通过 HTTP POST 发送一行日志
+func (s *NetworkSink) postHTTP(line string) error {
+	resp, err := s.client.Post(s.addr, "text/plain", bytes.NewBufferString(line+"\n"))
+	if err != nil {
+		return fmt.Errorf("network sink http post failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("network sink http post returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close 实现 Sink 接口
+func (s *NetworkSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		return s.conn.Close()
+	}
+	return nil
+}
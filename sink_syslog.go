@@ -0,0 +1,110 @@
+/*
+Copyright 2024 WJQserver Studio. WJQserver Studio 2.0 License.
+*/
+
+package logger
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// syslog 等级与 RFC5424 Severity 的映射（Facility 固定使用 user-level，值为 1）
+var syslogSeverity = map[int]int{
+	LevelDump:  7, // debug
+	LevelDebug: 7, // debug
+	LevelInfo:  6, // informational
+	LevelWarn:  4, // warning
+	LevelError: 3, // error
+}
+
+const syslogFacilityUser = 1
+
+// SyslogSink 通过 UDP/TCP/Unix Domain Socket 将日志以 RFC5424 格式发送到远端 syslog 收集器
+type SyslogSink struct {
+	network string // "udp"、"tcp" 或 "unixgram"/"unix"
+	addr    string
+	appName string
+	conn    net.Conn
+	mu      sync.Mutex
+}
+
+// NewSyslogSink 创建一个 SyslogSink，network 为 "udp"、"tcp"、"unixgram" 或 "unix"，
+// addr 为对应的地址或套接字路径，appName 写入 RFC5424 的 APP-NAME 字段
+func NewSyslogSink(network, addr, appName string) (*SyslogSink, error) {
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial syslog endpoint: %w", err)
+	}
+	return &SyslogSink{
+		network: network,
+		addr:    addr,
+		appName: appName,
+		conn:    conn,
+	}, nil
+}
+
+// Write 实现 Sink 接口，按 RFC5424 格式编码后发送
+func (s *SyslogSink) Write(entry *Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	msg := encodeRFC5424(entry, s.appName)
+	if _, err := s.conn.Write([]byte(msg)); err != nil {
+		// 网络 Sink 的连接可能已失效，尝试重连一次后再写
+		if rerr := s.reconnectLocked(); rerr != nil {
+			return fmt.Errorf("syslog write failed and reconnect failed: %w", rerr)
+		}
+		_, err = s.conn.Write([]byte(msg))
+		return err
+	}
+	return nil
+}
+
+// reconnectLocked 在持有 s.mu 的前提下重新建立连接
+func (s *SyslogSink) reconnectLocked() error {
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	conn, err := net.Dial(s.network, s.addr)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+// Close 实现 Sink 接口
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil {
+		return s.conn.Close()
+	}
+	return nil
+}
+
+// encodeRFC5424 按 RFC5424 规范编码一条日志记录：
+// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+func encodeRFC5424(entry *Entry, appName string) string {
+	pri := syslogFacilityUser*8 + syslogSeverity[entry.Level]
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	if appName == "" {
+		appName = filepath.Base(os.Args[0])
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %d - - %s\n",
+		pri,
+		entry.Time.Format(time.RFC3339),
+		hostname,
+		appName,
+		os.Getpid(),
+		entry.Message,
+	)
+}
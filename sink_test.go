@@ -0,0 +1,205 @@
+package logger
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestConsoleSinkConcurrentWriteAndSetEncoder 在 Write 与 SetColorize/SetEncoder
+// 并发执行时不应触发数据竞争（用 go test -race 验证）
+func TestConsoleSinkConcurrentWriteAndSetEncoder(t *testing.T) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	sink := NewConsoleSink(devNull)
+	entry := &Entry{Time: time.Now(), Level: LevelInfo, Message: "race check"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = sink.Write(entry)
+		}()
+		go func() {
+			defer wg.Done()
+			sink.SetColorize(i%2 == 0)
+			sink.SetEncoder(JSONEncoder{})
+		}()
+	}
+	wg.Wait()
+}
+
+// TestNetworkSinkConcurrentWriteAndSetEncoder 在 Write 与 SetEncoder 并发执行时
+// 不应触发数据竞争（用 go test -race 验证）；目标地址无需可达，Write 失败也无妨
+func TestNetworkSinkConcurrentWriteAndSetEncoder(t *testing.T) {
+	sink := &NetworkSink{
+		mode:    "http",
+		addr:    "http://127.0.0.1:0/does-not-exist",
+		client:  &http.Client{Timeout: 50 * time.Millisecond},
+		encoder: TextEncoder{},
+	}
+	entry := &Entry{Time: time.Now(), Level: LevelInfo, Message: "race check"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = sink.Write(entry)
+		}()
+		go func() {
+			defer wg.Done()
+			sink.SetEncoder(LogfmtEncoder{})
+		}()
+	}
+	wg.Wait()
+}
+
+// TestNetworkSinkReconnectDoesNotBlockOtherCalls 验证重连退避进行时，
+// SetEncoder/Close 这类只需要读写字段的调用不会被一起卡住（它们不经过 reconnectMu）
+func TestNetworkSinkReconnectDoesNotBlockOtherCalls(t *testing.T) {
+	sink := &NetworkSink{
+		mode:    "tcp",
+		addr:    "127.0.0.1:1", // 没有监听者，拨号会快速失败，从而进入退避循环
+		minBack: 50 * time.Millisecond,
+		maxBack: 200 * time.Millisecond,
+		encoder: TextEncoder{},
+	}
+	entry := &Entry{Time: time.Now(), Level: LevelInfo, Message: "race check"}
+
+	writeDone := make(chan struct{})
+	go func() {
+		_ = sink.Write(entry) // 触发重连退避，耗时数百毫秒
+		close(writeDone)
+	}()
+
+	time.Sleep(20 * time.Millisecond) // 等 Write 进入退避循环
+
+	setDone := make(chan struct{})
+	go func() {
+		sink.SetEncoder(JSONEncoder{})
+		close(setDone)
+	}()
+
+	select {
+	case <-setDone:
+	case <-time.After(150 * time.Millisecond):
+		t.Fatal("SetEncoder blocked behind the in-progress reconnect backoff")
+	}
+
+	<-writeDone
+}
+
+// TestFileSinkWriteMatchesEncoder 验证 FileSink.Write 落盘的内容就是 Encoder 渲染出的字节，
+// 不会被 Printf 的格式化逻辑额外改写
+func TestFileSinkWriteMatchesEncoder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "filesink.log")
+	sink, err := NewFileSink(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	sink.SetEncoder(JSONEncoder{})
+
+	entry := &Entry{Time: time.Now(), Level: LevelInfo, Message: "hello world"}
+	want := string(JSONEncoder{}.Encode(entry))
+
+	if err := sink.Write(entry); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// s.logger 内部走异步缓冲管线，落盘发生在后台 goroutine，这里轮询等待其完成
+	deadline := time.Now().Add(2 * time.Second)
+	var data []byte
+	for time.Now().Before(deadline) {
+		data, err = os.ReadFile(path)
+		if err == nil && len(data) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if len(data) == 0 {
+		t.Fatal("timed out waiting for FileSink to flush the entry")
+	}
+	if got := strings.TrimRight(string(data), "\n"); got != want {
+		t.Errorf("file content = %q, want %q", got, want)
+	}
+}
+
+// newArchive 在 dir 下创建一个名为 base.<ts 按本地时区格式化> 的空归档文件，
+// 模拟 rotateLogFile 按本地时区生成的归档命名
+func newArchive(t *testing.T, dir, base string, ts time.Time) string {
+	t.Helper()
+	name := filepath.Join(dir, base+"."+ts.Format("20060102-150405"))
+	if err := os.WriteFile(name, nil, 0666); err != nil {
+		t.Fatalf("failed to create archive %s: %v", name, err)
+	}
+	return name
+}
+
+// TestFileSinkSweepArchivesMaxAge 验证按 MaxAgeDays 清理归档时，时间戳按本地时区解析，
+// 不会因为默认按 UTC 解析而产生时区偏移误差
+func TestFileSinkSweepArchivesMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	sink, err := NewFileSink(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	defer sink.Close()
+
+	base := filepath.Base(path)
+	oldArchive := newArchive(t, dir, base, time.Now().Add(-10*24*time.Hour))
+	freshArchive := newArchive(t, dir, base, time.Now().Add(-1*time.Hour))
+
+	sink.policy.Store(RotationPolicy{MaxAgeDays: 5})
+	sink.sweepArchives()
+
+	if _, err := os.Stat(oldArchive); !os.IsNotExist(err) {
+		t.Errorf("expected archive older than MaxAgeDays to be pruned: %s", oldArchive)
+	}
+	if _, err := os.Stat(freshArchive); err != nil {
+		t.Errorf("expected archive within MaxAgeDays to be kept: %v", err)
+	}
+}
+
+// TestFileSinkSweepArchivesMaxBackups 验证超出 MaxBackups 时，从最旧的归档开始清理
+func TestFileSinkSweepArchivesMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	sink, err := NewFileSink(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	defer sink.Close()
+
+	base := filepath.Base(path)
+	oldest := newArchive(t, dir, base, time.Now().Add(-3*time.Hour))
+	middle := newArchive(t, dir, base, time.Now().Add(-2*time.Hour))
+	newest := newArchive(t, dir, base, time.Now().Add(-1*time.Hour))
+
+	sink.policy.Store(RotationPolicy{MaxBackups: 2})
+	sink.sweepArchives()
+
+	if _, err := os.Stat(oldest); !os.IsNotExist(err) {
+		t.Errorf("expected oldest archive beyond MaxBackups to be pruned: %s", oldest)
+	}
+	if _, err := os.Stat(middle); err != nil {
+		t.Errorf("expected archive within MaxBackups to be kept: %v", err)
+	}
+	if _, err := os.Stat(newest); err != nil {
+		t.Errorf("expected archive within MaxBackups to be kept: %v", err)
+	}
+}